@@ -0,0 +1,128 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ContainerMapping maps a container in the managed CronJob's job template to the
+// container in a source Deployment whose image it should track.
+type ContainerMapping struct {
+	// CronJobContainer is the container name in the CronJob's job template.
+	CronJobContainer string `json:"cronJobContainer"`
+	// DeploymentContainer is the container name in the source Deployment's pod template.
+	DeploymentContainer string `json:"deploymentContainer"`
+}
+
+// SourceDeploymentRef identifies a Deployment that a ManagedCronJob tracks, along with
+// how its containers map onto the CronJob's job template containers.
+type SourceDeploymentRef struct {
+	// Name is the name of the source Deployment, in the same namespace as the ManagedCronJob.
+	Name string `json:"name"`
+	// ContainerMappings maps CronJob containers to Deployment containers by name. If empty,
+	// containers are matched by identical name.
+	// +optional
+	ContainerMappings []ContainerMapping `json:"containerMappings,omitempty"`
+}
+
+// SyncField names a PodSpec field projected from a source Deployment onto the managed
+// CronJob's job template, beyond the container images that are always synced.
+// +kubebuilder:validation:Enum=nodeSelector;tolerations;affinity;resources;env
+type SyncField string
+
+const (
+	SyncFieldNodeSelector SyncField = "nodeSelector"
+	SyncFieldTolerations  SyncField = "tolerations"
+	SyncFieldAffinity     SyncField = "affinity"
+	SyncFieldResources    SyncField = "resources"
+	SyncFieldEnv          SyncField = "env"
+)
+
+// SyncPolicy controls which additional PodSpec fields are projected from a source Deployment
+// onto the managed CronJob's job template.
+type SyncPolicy struct {
+	// Fields lists which PodSpec fields to project, in addition to container images. Env vars
+	// are merged by name with the job template's own vars winning; resources are copied
+	// per-container by name.
+	// +optional
+	Fields []SyncField `json:"fields,omitempty"`
+}
+
+// ManagedCronJobSpec defines the desired state of a ManagedCronJob.
+type ManagedCronJobSpec struct {
+	// CronJobName is the name of the batchv1.CronJob owned by this ManagedCronJob, in the
+	// same namespace. The controller creates it if it does not already exist.
+	CronJobName string `json:"cronJobName"`
+
+	// SourceDeployments lists the Deployments whose container images are projected onto the
+	// managed CronJob's job template.
+	// +optional
+	SourceDeployments []SourceDeploymentRef `json:"sourceDeployments,omitempty"`
+
+	// SyncPolicy controls projection of PodSpec fields (nodeSelector, tolerations, affinity,
+	// resources, env) from the source Deployment onto the job template, beyond images.
+	// +optional
+	SyncPolicy SyncPolicy `json:"syncPolicy,omitempty"`
+
+	// ScheduleOverride, if set, is used instead of the CronJob's existing schedule. It is also
+	// the schedule used when the controller creates CronJobName because it does not yet exist;
+	// without it, a missing CronJob cannot be created.
+	// +optional
+	ScheduleOverride string `json:"scheduleOverride,omitempty"`
+
+	// SuspendOverride, if set, forces the CronJob's suspend field to this value.
+	// +optional
+	SuspendOverride *bool `json:"suspendOverride,omitempty"`
+
+	// EnvOverrides are environment variables applied to every container of the job template,
+	// taking precedence over values propagated from a source Deployment.
+	// +optional
+	EnvOverrides []corev1.EnvVar `json:"envOverrides,omitempty"`
+
+	// ImagePullSecrets are added to the job template's pod spec, per-container image pulls.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+}
+
+// ManagedCronJobStatus defines the observed state of a ManagedCronJob.
+type ManagedCronJobStatus struct {
+	// CronJobRef names the batchv1.CronJob currently owned by this ManagedCronJob.
+	// +optional
+	CronJobRef *corev1.LocalObjectReference `json:"cronJobRef,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the ManagedCronJob's state.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=mcj
+
+// ManagedCronJob binds a batchv1.CronJob to one or more source Deployments so that the
+// CronJob's job template tracks the Deployments' images without relying on label or
+// annotation heuristics.
+type ManagedCronJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ManagedCronJobSpec   `json:"spec,omitempty"`
+	Status ManagedCronJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ManagedCronJobList contains a list of ManagedCronJob.
+type ManagedCronJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ManagedCronJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ManagedCronJob{}, &ManagedCronJobList{})
+}
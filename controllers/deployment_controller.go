@@ -3,6 +3,8 @@ package controllers
 import (
     "context"
     "fmt"
+    "reflect"
+    "sync"
 
     appsv1 "k8s.io/api/apps/v1"
     batchv1 "k8s.io/api/batch/v1"
@@ -15,10 +17,17 @@ import (
     ctrl "sigs.k8s.io/controller-runtime"
     "sigs.k8s.io/controller-runtime/pkg/client"
     "sigs.k8s.io/controller-runtime/pkg/controller"
+    "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+    "sigs.k8s.io/controller-runtime/pkg/handler"
     "sigs.k8s.io/controller-runtime/pkg/log"
+    "sigs.k8s.io/controller-runtime/pkg/reconcile"
 
     "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
     "go.opentelemetry.io/otel/trace"
+
+    "github.com/example/cronjob-controller/api/v1alpha1"
 )
 
 // DeploymentReconciler watches Deployments and updates related CronJobs' images,
@@ -29,8 +38,24 @@ type DeploymentReconciler struct {
     Recorder record.EventRecorder
     // OpenTelemetry tracer and metric instruments
     Tracer trace.Tracer
+
+    // RunOnUpdateEnabled gates the on-demand Job trigger feature cluster-wide. When false,
+    // the per-CronJob run-on-update annotation is ignored. Set from the --run-on-update flag.
+    RunOnUpdateEnabled bool
+
+    // triggerLimiters rate-limits on-demand Job creation per CronJob so a hot reconcile loop
+    // cannot fork unbounded Jobs. Keyed by types.NamespacedName, values are *rate.Limiter.
+    triggerLimiters sync.Map
 }
 
+const (
+    // mcjSourceDeploymentIndexKey indexes ManagedCronJobs by the names of the Deployments
+    // they declare in spec.sourceDeployments.
+    mcjSourceDeploymentIndexKey = "spec.sourceDeployments.name"
+    // jobOwnerCronJobUIDIndexKey indexes Jobs by the UID of their owning CronJob.
+    jobOwnerCronJobUIDIndexKey = "metadata.ownerReferences.cronJobUID"
+)
+
 var (
     syncsTotal = prometheus.NewCounterVec(
         prometheus.CounterOpts{
@@ -60,6 +85,13 @@ var (
         },
         []string{"namespace", "deployment"},
     )
+    jobsTriggered = prometheus.NewCounterVec(
+        prometheus.CounterOpts{
+            Name: "cronjob_image_sync_jobs_triggered_total",
+            Help: "Total number of on-demand Jobs triggered after an image sync",
+        },
+        []string{"namespace", "cronjob"},
+    )
 )
 
 // SetupWithManager registers the reconciler with the manager.
@@ -69,6 +101,7 @@ func (r *DeploymentReconciler) SetupWithManager(mgr ctrl.Manager) error {
     _ = prometheus.Register(cronjobsUpdated)
     _ = prometheus.Register(jobsDeleted)
     _ = prometheus.Register(syncErrors)
+    _ = prometheus.Register(jobsTriggered)
 
     // set the event recorder if not provided
     if r.Recorder == nil {
@@ -80,19 +113,111 @@ func (r *DeploymentReconciler) SetupWithManager(mgr ctrl.Manager) error {
         r.Tracer = otel.Tracer("cronjob-controller")
     }
 
+    // Index ManagedCronJobs by the Deployments they reference, so findCronJobsForDeployment
+    // can do a single indexed List instead of scanning every ManagedCronJob in the namespace.
+    if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.ManagedCronJob{}, mcjSourceDeploymentIndexKey, func(obj client.Object) []string {
+        mcj := obj.(*v1alpha1.ManagedCronJob)
+        names := make([]string, 0, len(mcj.Spec.SourceDeployments))
+        for _, ref := range mcj.Spec.SourceDeployments {
+            names = append(names, ref.Name)
+        }
+        return names
+    }); err != nil {
+        return err
+    }
+
+    // Index Jobs by their owning CronJob's UID, so listJobsForCronJob can look up only the
+    // Jobs a CronJob owns instead of listing and scanning every Job in the namespace.
+    if err := mgr.GetFieldIndexer().IndexField(context.Background(), &batchv1.Job{}, jobOwnerCronJobUIDIndexKey, func(obj client.Object) []string {
+        job := obj.(*batchv1.Job)
+        var uids []string
+        for _, owner := range job.OwnerReferences {
+            if owner.Kind == "CronJob" {
+                uids = append(uids, string(owner.UID))
+            }
+        }
+        return uids
+    }); err != nil {
+        return err
+    }
+
     return ctrl.NewControllerManagedBy(mgr).
         For(&appsv1.Deployment{}).
+        Watches(&batchv1.CronJob{}, handler.EnqueueRequestsFromMapFunc(r.mapCronJobToDeploymentRequests)).
+        Watches(&batchv1.Job{}, handler.EnqueueRequestsFromMapFunc(r.mapJobToDeploymentRequests)).
         WithOptions(controller.Options{MaxConcurrentReconciles: 2}).
         Complete(r)
 }
 
+// mapCronJobToDeploymentRequests maps a CronJob event back to the Deployments declared by its
+// owning ManagedCronJob, so edits to the CronJob (or its recreation after deletion) trigger a
+// reconcile instead of waiting for the next Deployment change.
+func (r *DeploymentReconciler) mapCronJobToDeploymentRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+    cj, ok := obj.(*batchv1.CronJob)
+    if !ok {
+        return nil
+    }
+
+    var mcjList v1alpha1.ManagedCronJobList
+    if err := r.List(ctx, &mcjList, client.InNamespace(cj.Namespace)); err != nil {
+        log.FromContext(ctx).Error(err, "failed to list ManagedCronJobs while mapping CronJob event", "cronjob", cj.Name)
+        return nil
+    }
+
+    var reqs []reconcile.Request
+    for _, mcj := range mcjList.Items {
+        if mcj.Spec.CronJobName != cj.Name {
+            continue
+        }
+        for _, ref := range mcj.Spec.SourceDeployments {
+            reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cj.Namespace, Name: ref.Name}})
+        }
+    }
+    return reqs
+}
+
+// mapJobToDeploymentRequests maps a Job event back to the Deployments managing the Job's owning
+// CronJob, so a Job that fails to delete (or is otherwise retried) gets picked back up.
+func (r *DeploymentReconciler) mapJobToDeploymentRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+    job, ok := obj.(*batchv1.Job)
+    if !ok {
+        return nil
+    }
+
+    for _, owner := range job.OwnerReferences {
+        if owner.Kind != "CronJob" {
+            continue
+        }
+        var cj batchv1.CronJob
+        if err := r.Get(ctx, types.NamespacedName{Namespace: job.Namespace, Name: owner.Name}, &cj); err != nil {
+            continue
+        }
+        return r.mapCronJobToDeploymentRequests(ctx, &cj)
+    }
+    return nil
+}
+
 // Reconcile reacts to Deployment changes.
 func (r *DeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-    logger := log.FromContext(ctx)
+    ctx, span := r.Tracer.Start(ctx, "DeploymentReconciler.Reconcile", trace.WithAttributes(
+        attribute.String("k8s.namespace", req.Namespace),
+        attribute.String("k8s.deployment.name", req.Name),
+    ))
+    defer span.End()
+
+    logger := log.FromContext(ctx).WithValues("traceID", span.SpanContext().TraceID().String())
+    ctx = log.IntoContext(ctx, logger)
+
     var deploy appsv1.Deployment
     if err := r.Get(ctx, req.NamespacedName, &deploy); err != nil {
-        return ctrl.Result{}, client.IgnoreNotFound(err)
+        if client.IgnoreNotFound(err) == nil {
+            return ctrl.Result{}, nil
+        }
+        span.RecordError(err)
+        span.SetStatus(codes.Error, "failed to get deployment")
+        return ctrl.Result{}, err
     }
+    span.SetAttributes(attribute.String("k8s.deployment.resource_version", deploy.ResourceVersion))
 
     logger.Info("reconciling deployment", "deployment", req.NamespacedName)
     // metrics: record a reconcile invocation (Prometheus)
@@ -101,125 +226,180 @@ func (r *DeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
     // Find CronJobs that declare they are managed by this deployment.
     cronjobs, err := r.findCronJobsForDeployment(ctx, &deploy)
     if err != nil {
+        span.RecordError(err)
+        span.SetStatus(codes.Error, "findCronJobsForDeployment failed")
         return ctrl.Result{}, err
     }
+    span.AddEvent("cronjobs found", trace.WithAttributes(attribute.Int("count", len(cronjobs))))
 
-    // Build a map of container image by container name from the Deployment
-    imageByName := make(map[string]string)
-    for _, c := range deploy.Spec.Template.Spec.Containers {
-        imageByName[c.Name] = c.Image
-    }
-
-    for _, cj := range cronjobs {
+    var cronjobsUpdatedCount, jobsDeletedCount int
+    for _, binding := range cronjobs {
+        cj := binding.CronJob
         updated := false
-        // Update images on CronJob's job template
-        for i, c := range cj.Spec.JobTemplate.Spec.Template.Spec.Containers {
-            if img, ok := imageByName[c.Name]; ok {
-                if c.Image != img {
-                    cj.Spec.JobTemplate.Spec.Template.Spec.Containers[i].Image = img
-                    updated = true
+
+        // Sync container images using the ManagedCronJob's ContainerMappings (or same-name
+        // matching when no mapping is declared for a container).
+        if ref := findSourceDeploymentRef(&binding.ManagedCronJob, deploy.Name); ref != nil {
+            deploymentContainerFor := containerNameMapper(ref)
+            for i := range cj.Spec.JobTemplate.Spec.Template.Spec.Containers {
+                c := &cj.Spec.JobTemplate.Spec.Template.Spec.Containers[i]
+                srcName, ok := deploymentContainerFor(c.Name)
+                if !ok {
+                    continue
+                }
+                srcContainer := findContainerByName(deploy.Spec.Template.Spec.Containers, srcName)
+                if srcContainer == nil {
+                    continue
                 }
-            } else {
-                // if no matching name, optionally sync first container image
-                // (useful when names differ); fallback: use first deployment image
-                if len(deploy.Spec.Template.Spec.Containers) > 0 {
-                    fallback := deploy.Spec.Template.Spec.Containers[0].Image
-                    if c.Image != fallback {
-                        cj.Spec.JobTemplate.Spec.Template.Spec.Containers[i].Image = fallback
-                        updated = true
-                    }
+                if c.Image != srcContainer.Image {
+                    c.Image = srcContainer.Image
+                    updated = true
                 }
             }
         }
 
+        // Project any additional PodSpec fields (nodeSelector, tolerations, affinity,
+        // resources, env) the ManagedCronJob's SyncPolicy asks for.
+        if syncPodSpecFields(&binding.ManagedCronJob, &deploy, &cj) {
+            updated = true
+        }
+        span.AddEvent("image diff decided", trace.WithAttributes(
+            attribute.String("cronjob", cj.Name),
+            attribute.Bool("updated", updated),
+        ))
+
         if updated {
             if err := r.Update(ctx, &cj); err != nil {
                 logger.Error(err, "failed to update cronjob", "cronjob", types.NamespacedName{Namespace: cj.Namespace, Name: cj.Name})
+                span.RecordError(err)
                 syncErrors.WithLabelValues(deploy.Namespace, deploy.Name).Inc()
                 r.Recorder.Event(&deploy, corev1.EventTypeWarning, "UpdateFailed", fmt.Sprintf("failed to update CronJob %s: %v", cj.Name, err))
                 return ctrl.Result{}, err
             }
+            cronjobsUpdatedCount++
             cronjobsUpdated.WithLabelValues(cj.Namespace, cj.Name).Inc()
             r.Recorder.Event(&cj, corev1.EventTypeNormal, "CronJobUpdated", fmt.Sprintf("Updated job template images from Deployment %s/%s", deploy.Namespace, deploy.Name))
 
+            previousJobs, err := r.listJobsForCronJob(ctx, &cj)
+            if err != nil {
+                logger.Error(err, "failed to list jobs for cronjob", "cronjob", cj.Name)
+                span.RecordError(err)
+                syncErrors.WithLabelValues(deploy.Namespace, deploy.Name).Inc()
+                return ctrl.Result{}, err
+            }
+
             // Delete existing Jobs created by this CronJob so new Jobs use updated image
-            if err := r.deleteJobsForCronJob(ctx, &cj); err != nil {
+            deleted, err := r.deleteJobsForCronJob(ctx, &cj, previousJobs)
+            if err != nil {
                 logger.Error(err, "failed to delete jobs for cronjob", "cronjob", cj.Name)
+                span.RecordError(err)
                 syncErrors.WithLabelValues(deploy.Namespace, deploy.Name).Inc()
                 r.Recorder.Event(&cj, corev1.EventTypeWarning, "DeleteJobsFailed", fmt.Sprintf("failed to delete Jobs for CronJob %s: %v", cj.Name, err))
                 return ctrl.Result{}, err
             }
+            jobsDeletedCount += deleted
 
             logger.Info("updated cronjob image and deleted related jobs", "cronjob", cj.Name)
             r.Recorder.Event(&cj, corev1.EventTypeNormal, "JobsRecreated", "Deleted existing Jobs so future runs use the updated image")
+
+            if err := r.maybeTriggerJob(ctx, &cj, &deploy, previousJobs); err != nil {
+                logger.Error(err, "failed to trigger on-demand job for cronjob", "cronjob", cj.Name)
+                span.RecordError(err)
+                syncErrors.WithLabelValues(deploy.Namespace, deploy.Name).Inc()
+                return ctrl.Result{}, err
+            }
         } else {
             logger.Info("cronjob already up-to-date", "cronjob", cj.Name)
         }
     }
+    span.SetAttributes(
+        attribute.Int("cronjobs.updated", cronjobsUpdatedCount),
+        attribute.Int("jobs.deleted", jobsDeletedCount),
+    )
 
     return ctrl.Result{}, nil
 }
 
-// findCronJobsForDeployment lists CronJobs in the Deployment namespace and returns those
-// that indicate they are managed by the Deployment. Matching is done by:
-// - label `managed-by-deployment=<deployment-name>` OR
-// - annotation `controller.example.com/managed-by-deployment` with value `<ns>/<name>`
-func (r *DeploymentReconciler) findCronJobsForDeployment(ctx context.Context, d *appsv1.Deployment) ([]batchv1.CronJob, error) {
-    var list batchv1.CronJobList
-    if err := r.List(ctx, &list, &client.ListOptions{Namespace: d.Namespace}); err != nil {
+// managedCronJobBinding pairs a CronJob with the ManagedCronJob that owns it, so Reconcile can
+// consult the ManagedCronJob's SyncPolicy and container mappings while updating the CronJob.
+type managedCronJobBinding struct {
+    CronJob        batchv1.CronJob
+    ManagedCronJob v1alpha1.ManagedCronJob
+}
+
+// findCronJobsForDeployment returns the CronJobs owned by ManagedCronJobs that declare the
+// given Deployment as one of their sourceDeployments. This replaces the old label/annotation/
+// shared-image heuristics with a proper declarative binding via the ManagedCronJob CRD, backed
+// by the mcjSourceDeploymentIndexKey field indexer so the lookup is O(matches).
+func (r *DeploymentReconciler) findCronJobsForDeployment(ctx context.Context, d *appsv1.Deployment) ([]managedCronJobBinding, error) {
+    ctx, span := r.Tracer.Start(ctx, "DeploymentReconciler.findCronJobsForDeployment")
+    defer span.End()
+
+    var mcjList v1alpha1.ManagedCronJobList
+    if err := r.List(ctx, &mcjList, client.InNamespace(d.Namespace), client.MatchingFields{mcjSourceDeploymentIndexKey: d.Name}); err != nil {
+        span.RecordError(err)
         return nil, err
     }
 
-    var out []batchv1.CronJob
-    for _, cj := range list.Items {
-        if val, ok := cj.Labels["managed-by-deployment"]; ok && val == d.Name {
-            out = append(out, cj)
-            continue
+    var out []managedCronJobBinding
+    for i := range mcjList.Items {
+        mcj := &mcjList.Items[i]
+
+        var cj batchv1.CronJob
+        key := types.NamespacedName{Namespace: mcj.Namespace, Name: mcj.Spec.CronJobName}
+        if err := r.Get(ctx, key, &cj); err != nil {
+            if client.IgnoreNotFound(err) == nil {
+                log.FromContext(ctx).Info("managed cronjob's target CronJob not found, skipping", "managedcronjob", mcj.Name, "cronjob", key)
+                continue
+            }
+            span.RecordError(err)
+            return nil, err
         }
-        if ann, ok := cj.Annotations["controller.example.com/managed-by-deployment"]; ok && ann == fmt.Sprintf("%s/%s", d.Namespace, d.Name) {
-            out = append(out, cj)
-            continue
+
+        // Only write the owner reference back when it actually changes: SetControllerReference
+        // is deterministic given the same owner, so comparing before/after avoids an Update (and
+        // therefore a CronJob watch event) on every reconcile, which would otherwise re-enqueue
+        // this same Deployment forever via mapCronJobToDeploymentRequests.
+        before := cj.OwnerReferences
+        if err := controllerutil.SetControllerReference(mcj, &cj, r.Scheme); err != nil {
+            span.RecordError(err)
+            return nil, err
         }
-        // Also allow matching by image equality: if any container image in CronJob equals any in Deployment
-        if hasSharedImage(&cj, d) {
-            out = append(out, cj)
-            continue
+        if !reflect.DeepEqual(before, cj.OwnerReferences) {
+            if err := r.Update(ctx, &cj); err != nil {
+                span.RecordError(err)
+                return nil, err
+            }
         }
+
+        out = append(out, managedCronJobBinding{CronJob: cj, ManagedCronJob: *mcj})
     }
     return out, nil
 }
 
-func hasSharedImage(cj *batchv1.CronJob, d *appsv1.Deployment) bool {
-    depImages := make(map[string]struct{})
-    for _, c := range d.Spec.Template.Spec.Containers {
-        depImages[c.Image] = struct{}{}
-    }
-    for _, c := range cj.Spec.JobTemplate.Spec.Template.Spec.Containers {
-        if _, ok := depImages[c.Image]; ok {
-            return true
-        }
+// listJobsForCronJob returns the Jobs owned by the given CronJob, using the
+// jobOwnerCronJobUIDIndexKey field indexer so the lookup is O(matches) instead of a full
+// namespace List plus linear scan.
+func (r *DeploymentReconciler) listJobsForCronJob(ctx context.Context, cj *batchv1.CronJob) ([]batchv1.Job, error) {
+    var jobList batchv1.JobList
+    if err := r.List(ctx, &jobList, client.InNamespace(cj.Namespace), client.MatchingFields{jobOwnerCronJobUIDIndexKey: string(cj.UID)}); err != nil {
+        return nil, err
     }
-    return false
+    return jobList.Items, nil
 }
 
-// deleteJobsForCronJob deletes Jobs that are owned by the given CronJob using a foreground deletion
-// so pods are removed as well.
-
-func (r *DeploymentReconciler) deleteJobsForCronJob(ctx context.Context, cj *batchv1.CronJob) error {
-
-    var jobList batchv1.JobList
-    if err := r.List(ctx, &jobList, client.InNamespace(cj.Namespace)); err != nil {
-        return err
-    }
+// deleteJobsForCronJob deletes the given Jobs (expected to be owned by cj) using a foreground
+// deletion so pods are removed as well. It returns the number of Jobs deleted.
+func (r *DeploymentReconciler) deleteJobsForCronJob(ctx context.Context, cj *batchv1.CronJob, jobs []batchv1.Job) (int, error) {
+    ctx, span := r.Tracer.Start(ctx, "DeploymentReconciler.deleteJobsForCronJob", trace.WithAttributes(
+        attribute.String("cronjob", cj.Name),
+    ))
+    defer span.End()
 
     propagationPolicy := metav1.DeletePropagationForeground
+    deletedCount := 0
 
-    for _, job := range jobList.Items {
-
-        // Only jobs owned by this CronJob
-        if !isOwnedByCronJob(&job, cj) {
-            continue
-        }
+    for _, job := range jobs {
 
         jobName := job.Name
 
@@ -231,7 +411,8 @@ func (r *DeploymentReconciler) deleteJobsForCronJob(ctx context.Context, cj *bat
                 PropagationPolicy: &propagationPolicy,
             },
         ); err != nil  {
-            return err
+            span.RecordError(err)
+            return deletedCount, err
         }
 
         // Explicitly delete Pods created by this Job
@@ -244,9 +425,11 @@ func (r *DeploymentReconciler) deleteJobsForCronJob(ctx context.Context, cj *bat
                 "job-name": jobName,
             },
         ); err != nil {
-            return err
+            span.RecordError(err)
+            return deletedCount, err
         }
 
+        deletedCount++
         jobsDeleted.WithLabelValues(cj.Namespace, cj.Name).Inc()
 
         // Emit event (best-effort)
@@ -259,19 +442,7 @@ func (r *DeploymentReconciler) deleteJobsForCronJob(ctx context.Context, cj *bat
             cj.Name,
         )
     }
+    span.SetAttributes(attribute.Int("jobs.deleted", deletedCount))
 
-    return nil
-}
-
-
-
-func isOwnedByCronJob(job *batchv1.Job, cj *batchv1.CronJob) bool {
-    for _, owner := range job.OwnerReferences {
-        if owner.Kind == "CronJob" &&
-            owner.Name == cj.Name &&
-            owner.UID == cj.UID {
-            return true
-        }
-    }
-    return false
+    return deletedCount, nil
 }
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+    "context"
+    "fmt"
+
+    appsv1 "k8s.io/api/apps/v1"
+    batchv1 "k8s.io/api/batch/v1"
+    corev1 "k8s.io/api/core/v1"
+    metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+    "k8s.io/apimachinery/pkg/types"
+    "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+    "sigs.k8s.io/controller-runtime/pkg/log"
+
+    "golang.org/x/time/rate"
+)
+
+const (
+    // annotationRunOnUpdate opts a CronJob into the on-demand Job trigger feature.
+    annotationRunOnUpdate = "controller.example.com/run-on-update"
+    // annotationRunPolicy selects when a triggered Job is allowed to run; see RunPolicy.
+    annotationRunPolicy = "controller.example.com/run-policy"
+    // annotationTriggeredBy is set on Jobs created by the on-demand trigger, carrying the
+    // Deployment revision that caused the trigger.
+    annotationTriggeredBy = "controller.example.com/triggered-by"
+
+    triggerRateLimit = rate.Limit(1.0 / 60.0) // at most one triggered Job per CronJob per minute
+    triggerRateBurst = 1
+)
+
+// RunPolicy controls whether an on-demand Job is created after a CronJob's image is synced.
+type RunPolicy string
+
+const (
+    RunPolicyAlways              RunPolicy = "Always"
+    RunPolicyIfPreviousSucceeded RunPolicy = "IfPreviousSucceeded"
+    RunPolicyNever               RunPolicy = "Never"
+)
+
+// maybeTriggerJob creates a one-shot Job from cj's JobTemplate when cj opts into the
+// run-on-update annotation and the feature is enabled cluster-wide, so the updated image can be
+// validated immediately instead of waiting for the next schedule tick. previousJobs is used to
+// evaluate the IfPreviousSucceeded RunPolicy.
+func (r *DeploymentReconciler) maybeTriggerJob(ctx context.Context, cj *batchv1.CronJob, deploy *appsv1.Deployment, previousJobs []batchv1.Job) error {
+    if !r.RunOnUpdateEnabled {
+        return nil
+    }
+    if cj.Annotations[annotationRunOnUpdate] != "true" {
+        return nil
+    }
+
+    policy := RunPolicy(cj.Annotations[annotationRunPolicy])
+    if policy == "" {
+        policy = RunPolicyAlways
+    }
+
+    trigger, err := shouldTriggerJob(policy, previousJobs)
+    if err != nil {
+        return fmt.Errorf("cronjob %s/%s: %w", cj.Namespace, cj.Name, err)
+    }
+    if !trigger {
+        log.FromContext(ctx).Info("skipping on-demand job trigger", "cronjob", cj.Name, "runPolicy", policy)
+        return nil
+    }
+
+    if !r.triggerLimiterFor(types.NamespacedName{Namespace: cj.Namespace, Name: cj.Name}).Allow() {
+        log.FromContext(ctx).Info("rate-limited on-demand job trigger", "cronjob", cj.Name)
+        return nil
+    }
+
+    job := &batchv1.Job{
+        ObjectMeta: metav1.ObjectMeta{
+            GenerateName: cj.Name + "-triggered-",
+            Namespace:    cj.Namespace,
+            Labels:       cj.Spec.JobTemplate.Labels,
+            Annotations: map[string]string{
+                annotationTriggeredBy: fmt.Sprintf("%s/%s@%s", deploy.Namespace, deploy.Name, deploy.ResourceVersion),
+            },
+        },
+        Spec: cj.Spec.JobTemplate.Spec,
+    }
+    if err := controllerutil.SetControllerReference(cj, job, r.Scheme); err != nil {
+        return err
+    }
+
+    if err := r.Create(ctx, job); err != nil {
+        return err
+    }
+
+    jobsTriggered.WithLabelValues(cj.Namespace, cj.Name).Inc()
+    r.Recorder.Eventf(cj, corev1.EventTypeNormal, "JobTriggered", "Created on-demand Job %s from Deployment %s/%s update", job.Name, deploy.Namespace, deploy.Name)
+    return nil
+}
+
+// shouldTriggerJob decides, given policy and the CronJob's previousJobs, whether an on-demand
+// Job should be created. It is pure so the RunPolicy decision can be tested without a client.
+func shouldTriggerJob(policy RunPolicy, previousJobs []batchv1.Job) (bool, error) {
+    switch policy {
+    case RunPolicyNever:
+        return false, nil
+    case RunPolicyAlways:
+        return true, nil
+    case RunPolicyIfPreviousSucceeded:
+        return previousJobSucceeded(previousJobs), nil
+    default:
+        return false, fmt.Errorf("unknown %s value %q", annotationRunPolicy, policy)
+    }
+}
+
+// previousJobSucceeded reports whether the most recently created Job in jobs completed
+// successfully.
+func previousJobSucceeded(jobs []batchv1.Job) bool {
+    if len(jobs) == 0 {
+        return true
+    }
+
+    latest := jobs[0]
+    for _, job := range jobs[1:] {
+        if job.CreationTimestamp.After(latest.CreationTimestamp.Time) {
+            latest = job
+        }
+    }
+
+    for _, cond := range latest.Status.Conditions {
+        if cond.Type == batchv1.JobComplete && cond.Status == corev1.ConditionTrue {
+            return true
+        }
+    }
+    return false
+}
+
+// triggerLimiterFor returns the per-CronJob rate limiter, creating one on first use.
+func (r *DeploymentReconciler) triggerLimiterFor(key types.NamespacedName) *rate.Limiter {
+    limiter, _ := r.triggerLimiters.LoadOrStore(key, rate.NewLimiter(triggerRateLimit, triggerRateBurst))
+    return limiter.(*rate.Limiter)
+}
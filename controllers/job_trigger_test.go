@@ -0,0 +1,81 @@
+package controllers
+
+import (
+    "testing"
+    "time"
+
+    batchv1 "k8s.io/api/batch/v1"
+    corev1 "k8s.io/api/core/v1"
+    metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func jobAt(t time.Time, complete bool) batchv1.Job {
+    job := batchv1.Job{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(t)}}
+    if complete {
+        job.Status.Conditions = []batchv1.JobCondition{
+            {Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+        }
+    }
+    return job
+}
+
+func TestPreviousJobSucceeded(t *testing.T) {
+    now := time.Unix(1700000000, 0)
+
+    tests := []struct {
+        name string
+        jobs []batchv1.Job
+        want bool
+    }{
+        {name: "no previous jobs defaults to true", jobs: nil, want: true},
+        {name: "latest job complete", jobs: []batchv1.Job{jobAt(now, true)}, want: true},
+        {name: "latest job not complete", jobs: []batchv1.Job{jobAt(now, false)}, want: false},
+        {
+            name: "picks the most recently created job",
+            jobs: []batchv1.Job{
+                jobAt(now.Add(-time.Hour), false),
+                jobAt(now, true),
+            },
+            want: true,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := previousJobSucceeded(tt.jobs); got != tt.want {
+                t.Errorf("previousJobSucceeded() = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestShouldTriggerJob(t *testing.T) {
+    succeeded := []batchv1.Job{jobAt(time.Unix(0, 0), true)}
+    failed := []batchv1.Job{jobAt(time.Unix(0, 0), false)}
+
+    tests := []struct {
+        name    string
+        policy  RunPolicy
+        jobs    []batchv1.Job
+        want    bool
+        wantErr bool
+    }{
+        {name: "always triggers regardless of previous jobs", policy: RunPolicyAlways, jobs: failed, want: true},
+        {name: "never never triggers", policy: RunPolicyNever, jobs: succeeded, want: false},
+        {name: "if-previous-succeeded triggers when previous succeeded", policy: RunPolicyIfPreviousSucceeded, jobs: succeeded, want: true},
+        {name: "if-previous-succeeded skips when previous failed", policy: RunPolicyIfPreviousSucceeded, jobs: failed, want: false},
+        {name: "unknown policy is an error", policy: RunPolicy("Bogus"), jobs: nil, wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := shouldTriggerJob(tt.policy, tt.jobs)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("shouldTriggerJob() error = %v, wantErr %v", err, tt.wantErr)
+            }
+            if err == nil && got != tt.want {
+                t.Errorf("shouldTriggerJob() = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}
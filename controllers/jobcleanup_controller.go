@@ -0,0 +1,226 @@
+package controllers
+
+import (
+    "context"
+    "time"
+
+    batchv1 "k8s.io/api/batch/v1"
+    corev1 "k8s.io/api/core/v1"
+    apierrors "k8s.io/apimachinery/pkg/api/errors"
+    "k8s.io/apimachinery/pkg/labels"
+    metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+    "k8s.io/apimachinery/pkg/runtime"
+    "k8s.io/client-go/tools/record"
+    "github.com/prometheus/client_golang/prometheus"
+    ctrl "sigs.k8s.io/controller-runtime"
+    "sigs.k8s.io/controller-runtime/pkg/client"
+    "sigs.k8s.io/controller-runtime/pkg/controller"
+    "sigs.k8s.io/controller-runtime/pkg/handler"
+    "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// cleanupReason labels why a Job or Pod was deleted, for the per-reason metrics below.
+type cleanupReason string
+
+const (
+    reasonSuccessful cleanupReason = "successful"
+    reasonFailed     cleanupReason = "failed"
+    reasonPending    cleanupReason = "pending"
+    reasonOrphaned   cleanupReason = "orphaned"
+)
+
+var (
+    cleanupJobsDeleted = prometheus.NewCounterVec(
+        prometheus.CounterOpts{
+            Name: "cronjob_cleanup_jobs_deleted_total",
+            Help: "Total number of Jobs deleted by the cleanup controller, by reason",
+        },
+        []string{"namespace", "reason"},
+    )
+    cleanupPodsDeleted = prometheus.NewCounterVec(
+        prometheus.CounterOpts{
+            Name: "cronjob_cleanup_pods_deleted_total",
+            Help: "Total number of Pods deleted by the cleanup controller, by reason",
+        },
+        []string{"namespace", "reason"},
+    )
+)
+
+// JobCleanupReconciler deletes stale Completed/Failed/Pending Jobs and orphaned Pods once they
+// have aged past configurable retention windows, independent of which CronJob (if any) created
+// them. Only Jobs and Pods matching LabelSelector are considered, so operators opt in per
+// namespace or workload rather than having every Job in the cluster swept.
+type JobCleanupReconciler struct {
+    client.Client
+    Scheme   *runtime.Scheme
+    Recorder record.EventRecorder
+
+    // LabelSelector restricts cleanup to matching Jobs and Pods. A nil or empty selector
+    // matches nothing, so the controller is opt-in.
+    LabelSelector labels.Selector
+
+    DeleteSuccessfulAfter   time.Duration
+    DeleteFailedAfter       time.Duration
+    DeletePendingAfter      time.Duration
+    DeleteOrphanedPodsAfter time.Duration
+}
+
+// SetupWithManager registers the reconciler with the manager.
+func (r *JobCleanupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+    _ = prometheus.Register(cleanupJobsDeleted)
+    _ = prometheus.Register(cleanupPodsDeleted)
+
+    if r.Recorder == nil {
+        r.Recorder = mgr.GetEventRecorderFor("cronjob-cleanup-controller")
+    }
+    if r.LabelSelector == nil || r.LabelSelector.Empty() {
+        r.LabelSelector = labels.Nothing()
+    }
+
+    return ctrl.NewControllerManagedBy(mgr).
+        For(&batchv1.Job{}).
+        Watches(&corev1.Pod{}, &handler.EnqueueRequestForObject{}).
+        WithOptions(controller.Options{MaxConcurrentReconciles: 2}).
+        Complete(r)
+}
+
+// Reconcile is keyed by the NamespacedName of either a Job or a Pod; it looks up whichever
+// kind still exists and applies the matching retention policy.
+func (r *JobCleanupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+    logger := log.FromContext(ctx)
+
+    var job batchv1.Job
+    if err := r.Get(ctx, req.NamespacedName, &job); err == nil {
+        return r.reconcileJob(ctx, &job)
+    } else if !apierrors.IsNotFound(err) {
+        return ctrl.Result{}, err
+    }
+
+    var pod corev1.Pod
+    if err := r.Get(ctx, req.NamespacedName, &pod); err == nil {
+        return r.reconcilePod(ctx, &pod)
+    } else if !apierrors.IsNotFound(err) {
+        return ctrl.Result{}, err
+    }
+
+    logger.V(1).Info("job/pod no longer exists, nothing to clean up", "request", req.NamespacedName)
+    return ctrl.Result{}, nil
+}
+
+// reconcileJob deletes job if it is a Completed/Failed/Pending Job that has aged past its
+// retention window, or requeues for the remaining time otherwise.
+func (r *JobCleanupReconciler) reconcileJob(ctx context.Context, job *batchv1.Job) (ctrl.Result, error) {
+    if !r.LabelSelector.Matches(labels.Set(job.Labels)) {
+        return ctrl.Result{}, nil
+    }
+
+    reason, finishedAt, retention, ok := jobCleanupCandidate(job, r.DeleteSuccessfulAfter, r.DeleteFailedAfter, r.DeletePendingAfter)
+    if !ok || retention <= 0 {
+        return ctrl.Result{}, nil
+    }
+
+    age := time.Since(finishedAt)
+    if age < retention {
+        return ctrl.Result{RequeueAfter: retention - age}, nil
+    }
+
+    propagationPolicy := metav1.DeletePropagationBackground
+    if err := r.Delete(ctx, job, &client.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil {
+        if apierrors.IsNotFound(err) {
+            return ctrl.Result{}, nil
+        }
+        return ctrl.Result{}, err
+    }
+
+    cleanupJobsDeleted.WithLabelValues(job.Namespace, string(reason)).Inc()
+    r.Recorder.Eventf(job, corev1.EventTypeNormal, "JobCleanedUp", "Deleted %s Job past its %s retention window", reason, retention)
+    log.FromContext(ctx).Info("deleted stale job", "job", job.Name, "reason", reason, "retention", retention)
+
+    return ctrl.Result{}, nil
+}
+
+// reconcilePod deletes pod if it is orphaned (no controller OwnerReferences) and has aged past
+// DeleteOrphanedPodsAfter, or requeues for the remaining time otherwise.
+func (r *JobCleanupReconciler) reconcilePod(ctx context.Context, pod *corev1.Pod) (ctrl.Result, error) {
+    if !r.LabelSelector.Matches(labels.Set(pod.Labels)) {
+        return ctrl.Result{}, nil
+    }
+    if r.DeleteOrphanedPodsAfter <= 0 {
+        return ctrl.Result{}, nil
+    }
+    if metav1.GetControllerOf(pod) != nil {
+        return ctrl.Result{}, nil
+    }
+
+    finishedAt, ok := podFinishedAt(pod)
+    if !ok {
+        return ctrl.Result{}, nil
+    }
+
+    age := time.Since(finishedAt)
+    if age < r.DeleteOrphanedPodsAfter {
+        return ctrl.Result{RequeueAfter: r.DeleteOrphanedPodsAfter - age}, nil
+    }
+
+    if err := r.Delete(ctx, pod); err != nil {
+        if apierrors.IsNotFound(err) {
+            return ctrl.Result{}, nil
+        }
+        return ctrl.Result{}, err
+    }
+
+    cleanupPodsDeleted.WithLabelValues(pod.Namespace, string(reasonOrphaned)).Inc()
+    r.Recorder.Event(pod, corev1.EventTypeNormal, "OrphanedPodCleanedUp", "Deleted orphaned Pod past its retention window")
+    log.FromContext(ctx).Info("deleted orphaned pod", "pod", pod.Name, "retention", r.DeleteOrphanedPodsAfter)
+
+    return ctrl.Result{}, nil
+}
+
+// jobCleanupCandidate determines whether job is Completed, Failed, or Pending, and returns the
+// reason, the time to measure its age from, and the configured retention for that reason.
+// "Finish time" for Completed/Failed Jobs is the latest matching condition's
+// LastTransitionTime; Pending Jobs (no start time yet) are aged from CreationTimestamp.
+func jobCleanupCandidate(job *batchv1.Job, successfulAfter, failedAfter, pendingAfter time.Duration) (cleanupReason, time.Time, time.Duration, bool) {
+    var latest *batchv1.JobCondition
+    for i := range job.Status.Conditions {
+        cond := &job.Status.Conditions[i]
+        if cond.Status != corev1.ConditionTrue || (cond.Type != batchv1.JobComplete && cond.Type != batchv1.JobFailed) {
+            continue
+        }
+        if latest == nil || cond.LastTransitionTime.Time.After(latest.LastTransitionTime.Time) {
+            latest = cond
+        }
+    }
+    if latest != nil {
+        if latest.Type == batchv1.JobComplete {
+            return reasonSuccessful, latest.LastTransitionTime.Time, successfulAfter, true
+        }
+        return reasonFailed, latest.LastTransitionTime.Time, failedAfter, true
+    }
+
+    if job.Status.StartTime == nil {
+        return reasonPending, job.CreationTimestamp.Time, pendingAfter, true
+    }
+
+    return "", time.Time{}, 0, false
+}
+
+// podFinishedAt returns the latest terminated.finishedAt across pod's containers, or false if
+// the pod has no terminated containers yet.
+func podFinishedAt(pod *corev1.Pod) (time.Time, bool) {
+    var latest time.Time
+    found := false
+
+    for _, cs := range pod.Status.ContainerStatuses {
+        if cs.State.Terminated == nil {
+            continue
+        }
+        t := cs.State.Terminated.FinishedAt.Time
+        if !found || t.After(latest) {
+            latest = t
+            found = true
+        }
+    }
+
+    return latest, found
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+    "testing"
+    "time"
+
+    batchv1 "k8s.io/api/batch/v1"
+    corev1 "k8s.io/api/core/v1"
+    metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestJobCleanupCandidate(t *testing.T) {
+    const (
+        successfulAfter = time.Hour
+        failedAfter     = 2 * time.Hour
+        pendingAfter    = 10 * time.Minute
+    )
+    finished := metav1.NewTime(time.Unix(1700000000, 0))
+    created := metav1.NewTime(time.Unix(1699999000, 0))
+
+    tests := []struct {
+        name          string
+        job           *batchv1.Job
+        wantReason    cleanupReason
+        wantAt        time.Time
+        wantRetention time.Duration
+        wantOK        bool
+    }{
+        {
+            name: "completed job uses JobComplete transition time",
+            job: &batchv1.Job{
+                Status: batchv1.JobStatus{
+                    Conditions: []batchv1.JobCondition{
+                        {Type: batchv1.JobComplete, Status: corev1.ConditionTrue, LastTransitionTime: finished},
+                    },
+                },
+            },
+            wantReason: reasonSuccessful, wantAt: finished.Time, wantRetention: successfulAfter, wantOK: true,
+        },
+        {
+            name: "failed job uses JobFailed transition time",
+            job: &batchv1.Job{
+                Status: batchv1.JobStatus{
+                    Conditions: []batchv1.JobCondition{
+                        {Type: batchv1.JobFailed, Status: corev1.ConditionTrue, LastTransitionTime: finished},
+                    },
+                },
+            },
+            wantReason: reasonFailed, wantAt: finished.Time, wantRetention: failedAfter, wantOK: true,
+        },
+        {
+            name: "false conditions are ignored",
+            job: &batchv1.Job{
+                Status: batchv1.JobStatus{
+                    Conditions: []batchv1.JobCondition{
+                        {Type: batchv1.JobComplete, Status: corev1.ConditionFalse, LastTransitionTime: finished},
+                    },
+                },
+            },
+            wantReason: reasonPending, wantAt: time.Time{}, wantRetention: pendingAfter, wantOK: true,
+        },
+        {
+            name:       "job with no conditions and no start time is pending, aged from creation",
+            job:        &batchv1.Job{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created}},
+            wantReason: reasonPending, wantAt: created.Time, wantRetention: pendingAfter, wantOK: true,
+        },
+        {
+            name:   "running job (has StartTime, no terminal condition) is not a candidate",
+            job:    &batchv1.Job{Status: batchv1.JobStatus{StartTime: &finished}},
+            wantOK: false,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            reason, at, retention, ok := jobCleanupCandidate(tt.job, successfulAfter, failedAfter, pendingAfter)
+            if ok != tt.wantOK {
+                t.Fatalf("jobCleanupCandidate() ok = %v, want %v", ok, tt.wantOK)
+            }
+            if !ok {
+                return
+            }
+            if reason != tt.wantReason {
+                t.Errorf("jobCleanupCandidate() reason = %v, want %v", reason, tt.wantReason)
+            }
+            if !at.Equal(tt.wantAt) {
+                t.Errorf("jobCleanupCandidate() at = %v, want %v", at, tt.wantAt)
+            }
+            if retention != tt.wantRetention {
+                t.Errorf("jobCleanupCandidate() retention = %v, want %v", retention, tt.wantRetention)
+            }
+        })
+    }
+}
+
+func TestPodFinishedAt(t *testing.T) {
+    t.Run("no terminated containers", func(t *testing.T) {
+        pod := &corev1.Pod{}
+        if _, ok := podFinishedAt(pod); ok {
+            t.Error("podFinishedAt() ok = true, want false")
+        }
+    })
+
+    t.Run("picks the latest terminated container", func(t *testing.T) {
+        earlier := metav1.NewTime(time.Unix(1700000000, 0))
+        later := metav1.NewTime(time.Unix(1700000100, 0))
+        pod := &corev1.Pod{
+            Status: corev1.PodStatus{
+                ContainerStatuses: []corev1.ContainerStatus{
+                    {State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: earlier}}},
+                    {State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: later}}},
+                    {State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+                },
+            },
+        }
+        got, ok := podFinishedAt(pod)
+        if !ok {
+            t.Fatal("podFinishedAt() ok = false, want true")
+        }
+        if !got.Equal(later.Time) {
+            t.Errorf("podFinishedAt() = %v, want %v", got, later.Time)
+        }
+    })
+}
@@ -0,0 +1,226 @@
+package controllers
+
+import (
+    "context"
+    "fmt"
+    "reflect"
+
+    batchv1 "k8s.io/api/batch/v1"
+    corev1 "k8s.io/api/core/v1"
+    apierrors "k8s.io/apimachinery/pkg/api/errors"
+    apimeta "k8s.io/apimachinery/pkg/api/meta"
+    metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+    "k8s.io/apimachinery/pkg/runtime"
+    "k8s.io/apimachinery/pkg/types"
+    "k8s.io/client-go/tools/record"
+    ctrl "sigs.k8s.io/controller-runtime"
+    "sigs.k8s.io/controller-runtime/pkg/client"
+    "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+    "sigs.k8s.io/controller-runtime/pkg/log"
+
+    "github.com/example/cronjob-controller/api/v1alpha1"
+)
+
+// ManagedCronJobReconciler owns the lifecycle of the batchv1.CronJob named by a ManagedCronJob:
+// it creates the CronJob on first sight, keeps ScheduleOverride/SuspendOverride/EnvOverrides/
+// ImagePullSecrets applied, and reports what it did back onto ManagedCronJobStatus. Container
+// image syncing from source Deployments stays with DeploymentReconciler, which watches the
+// CronJobs this reconciler creates.
+type ManagedCronJobReconciler struct {
+    client.Client
+    Scheme   *runtime.Scheme
+    Recorder record.EventRecorder
+}
+
+// SetupWithManager registers the reconciler with the manager.
+func (r *ManagedCronJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
+    if r.Recorder == nil {
+        r.Recorder = mgr.GetEventRecorderFor("cronjob-controller")
+    }
+
+    return ctrl.NewControllerManagedBy(mgr).
+        For(&v1alpha1.ManagedCronJob{}).
+        Owns(&batchv1.CronJob{}).
+        Complete(r)
+}
+
+// Reconcile ensures the CronJob named by mcj.Spec.CronJobName exists and reflects mcj's
+// overrides, then records the outcome on mcj.Status.
+func (r *ManagedCronJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+    logger := log.FromContext(ctx)
+
+    var mcj v1alpha1.ManagedCronJob
+    if err := r.Get(ctx, req.NamespacedName, &mcj); err != nil {
+        return ctrl.Result{}, client.IgnoreNotFound(err)
+    }
+
+    cjKey := types.NamespacedName{Namespace: mcj.Namespace, Name: mcj.Spec.CronJobName}
+    var cj batchv1.CronJob
+    err := r.Get(ctx, cjKey, &cj)
+    switch {
+    case apierrors.IsNotFound(err):
+        created, err := r.createCronJob(ctx, &mcj)
+        if err != nil {
+            if statusErr := r.applyStatus(ctx, &mcj, nil, metav1.Condition{
+                Type:    "CronJobReady",
+                Status:  metav1.ConditionFalse,
+                Reason:  "CreateFailed",
+                Message: err.Error(),
+            }); statusErr != nil {
+                logger.Error(statusErr, "failed to update managedcronjob status", "managedcronjob", mcj.Name)
+            }
+            return ctrl.Result{}, err
+        }
+        cj = *created
+        logger.Info("created cronjob for managedcronjob", "managedcronjob", mcj.Name, "cronjob", cj.Name)
+        r.Recorder.Eventf(&mcj, corev1.EventTypeNormal, "CronJobCreated", "Created CronJob %s", cj.Name)
+    case err != nil:
+        return ctrl.Result{}, err
+    default:
+        if applyManagedCronJobOverrides(&mcj, &cj) {
+            if err := r.Update(ctx, &cj); err != nil {
+                return ctrl.Result{}, err
+            }
+            logger.Info("applied managedcronjob overrides to cronjob", "managedcronjob", mcj.Name, "cronjob", cj.Name)
+        }
+    }
+
+    if err := r.applyStatus(ctx, &mcj, &corev1.LocalObjectReference{Name: cj.Name}, metav1.Condition{
+        Type:    "CronJobReady",
+        Status:  metav1.ConditionTrue,
+        Reason:  "CronJobExists",
+        Message: fmt.Sprintf("CronJob %s exists and reflects the configured overrides", cj.Name),
+    }); err != nil {
+        return ctrl.Result{}, err
+    }
+
+    return ctrl.Result{}, nil
+}
+
+// applyStatus computes the status mcj should report and writes it via Status().Update only if it
+// differs from mcj's current status. Without this guard, every reconcile would write an
+// identical status, bumping mcj's resourceVersion and re-triggering this controller's own watch
+// on ManagedCronJob forever — the same class of self-sustaining loop fixed for the CronJob
+// owner-ref write in findCronJobsForDeployment.
+func (r *ManagedCronJobReconciler) applyStatus(ctx context.Context, mcj *v1alpha1.ManagedCronJob, cronJobRef *corev1.LocalObjectReference, cond metav1.Condition) error {
+    newStatus := mcj.Status.DeepCopy()
+    newStatus.CronJobRef = cronJobRef
+    newStatus.ObservedGeneration = mcj.Generation
+    cond.ObservedGeneration = mcj.Generation
+    apimeta.SetStatusCondition(&newStatus.Conditions, cond)
+
+    if reflect.DeepEqual(*newStatus, mcj.Status) {
+        return nil
+    }
+
+    mcj.Status = *newStatus
+    return r.Status().Update(ctx, mcj)
+}
+
+// createCronJob builds and creates the CronJob owned by mcj. ScheduleOverride supplies the
+// initial schedule, since ManagedCronJobSpec has no separate required schedule field; a
+// ManagedCronJob with no ScheduleOverride cannot create a CronJob that does not already exist.
+func (r *ManagedCronJobReconciler) createCronJob(ctx context.Context, mcj *v1alpha1.ManagedCronJob) (*batchv1.CronJob, error) {
+    if mcj.Spec.ScheduleOverride == "" {
+        return nil, fmt.Errorf("managedcronjob %s/%s: cronjob %q does not exist and spec.scheduleOverride is unset, so it cannot be created", mcj.Namespace, mcj.Name, mcj.Spec.CronJobName)
+    }
+
+    cj := &batchv1.CronJob{
+        ObjectMeta: metav1.ObjectMeta{
+            Name:      mcj.Spec.CronJobName,
+            Namespace: mcj.Namespace,
+        },
+        Spec: batchv1.CronJobSpec{
+            Schedule: mcj.Spec.ScheduleOverride,
+        },
+    }
+    applyManagedCronJobOverrides(mcj, cj)
+
+    if err := controllerutil.SetControllerReference(mcj, cj, r.Scheme); err != nil {
+        return nil, err
+    }
+    if err := r.Create(ctx, cj); err != nil {
+        return nil, err
+    }
+    return cj, nil
+}
+
+// applyManagedCronJobOverrides projects mcj's ScheduleOverride, SuspendOverride, EnvOverrides,
+// and ImagePullSecrets onto cj, reporting whether cj changed. It is idempotent, so repeated
+// calls with unchanged inputs report no change.
+func applyManagedCronJobOverrides(mcj *v1alpha1.ManagedCronJob, cj *batchv1.CronJob) bool {
+    changed := false
+
+    if mcj.Spec.ScheduleOverride != "" && cj.Spec.Schedule != mcj.Spec.ScheduleOverride {
+        cj.Spec.Schedule = mcj.Spec.ScheduleOverride
+        changed = true
+    }
+    if mcj.Spec.SuspendOverride != nil {
+        if cj.Spec.Suspend == nil || *cj.Spec.Suspend != *mcj.Spec.SuspendOverride {
+            cj.Spec.Suspend = mcj.Spec.SuspendOverride
+            changed = true
+        }
+    }
+
+    podSpec := &cj.Spec.JobTemplate.Spec.Template.Spec
+    if len(mcj.Spec.EnvOverrides) > 0 {
+        for i := range podSpec.Containers {
+            if setEnvOverrides(&podSpec.Containers[i], mcj.Spec.EnvOverrides) {
+                changed = true
+            }
+        }
+    }
+    if len(mcj.Spec.ImagePullSecrets) > 0 && addImagePullSecrets(podSpec, mcj.Spec.ImagePullSecrets) {
+        changed = true
+    }
+
+    return changed
+}
+
+// setEnvOverrides applies overrides onto c.Env, replacing any existing entry with the same name
+// so EnvOverrides take precedence over values propagated from a source Deployment. It reports
+// whether c.Env changed.
+func setEnvOverrides(c *corev1.Container, overrides []corev1.EnvVar) bool {
+    changed := false
+    for _, override := range overrides {
+        if existing := findEnvByName(c.Env, override.Name); existing != nil {
+            if !reflect.DeepEqual(*existing, override) {
+                *existing = override
+                changed = true
+            }
+            continue
+        }
+        c.Env = append(c.Env, override)
+        changed = true
+    }
+    return changed
+}
+
+func findEnvByName(env []corev1.EnvVar, name string) *corev1.EnvVar {
+    for i := range env {
+        if env[i].Name == name {
+            return &env[i]
+        }
+    }
+    return nil
+}
+
+// addImagePullSecrets appends any of secrets not already present in podSpec.ImagePullSecrets,
+// by name. It reports whether podSpec changed.
+func addImagePullSecrets(podSpec *corev1.PodSpec, secrets []corev1.LocalObjectReference) bool {
+    existing := make(map[string]bool, len(podSpec.ImagePullSecrets))
+    for _, s := range podSpec.ImagePullSecrets {
+        existing[s.Name] = true
+    }
+
+    changed := false
+    for _, s := range secrets {
+        if existing[s.Name] {
+            continue
+        }
+        podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, s)
+        existing[s.Name] = true
+        changed = true
+    }
+    return changed
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+    "testing"
+
+    batchv1 "k8s.io/api/batch/v1"
+    corev1 "k8s.io/api/core/v1"
+
+    "github.com/example/cronjob-controller/api/v1alpha1"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestApplyManagedCronJobOverrides(t *testing.T) {
+    newCronJob := func() *batchv1.CronJob {
+        return &batchv1.CronJob{
+            Spec: batchv1.CronJobSpec{
+                Schedule: "*/5 * * * *",
+                JobTemplate: batchv1.JobTemplateSpec{
+                    Spec: batchv1.JobSpec{
+                        Template: corev1.PodTemplateSpec{
+                            Spec: corev1.PodSpec{
+                                Containers: []corev1.Container{
+                                    {Name: "app", Env: []corev1.EnvVar{{Name: "FOO", Value: "from-deployment"}}},
+                                },
+                            },
+                        },
+                    },
+                },
+            },
+        }
+    }
+
+    mcj := &v1alpha1.ManagedCronJob{
+        Spec: v1alpha1.ManagedCronJobSpec{
+            ScheduleOverride: "0 * * * *",
+            SuspendOverride:  boolPtr(true),
+            EnvOverrides:     []corev1.EnvVar{{Name: "FOO", Value: "overridden"}, {Name: "BAR", Value: "added"}},
+            ImagePullSecrets: []corev1.LocalObjectReference{{Name: "regcred"}},
+        },
+    }
+
+    cj := newCronJob()
+    if changed := applyManagedCronJobOverrides(mcj, cj); !changed {
+        t.Fatal("applyManagedCronJobOverrides() = false, want true")
+    }
+    if cj.Spec.Schedule != "0 * * * *" {
+        t.Errorf("Schedule = %q, want %q", cj.Spec.Schedule, "0 * * * *")
+    }
+    if cj.Spec.Suspend == nil || !*cj.Spec.Suspend {
+        t.Errorf("Suspend = %v, want true", cj.Spec.Suspend)
+    }
+    env := cj.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env
+    if len(env) != 2 || env[0].Value != "overridden" || env[1].Name != "BAR" {
+        t.Errorf("Env = %v, want FOO=overridden plus BAR=added", env)
+    }
+    secrets := cj.Spec.JobTemplate.Spec.Template.Spec.ImagePullSecrets
+    if len(secrets) != 1 || secrets[0].Name != "regcred" {
+        t.Errorf("ImagePullSecrets = %v, want [regcred]", secrets)
+    }
+
+    // A second application of the same overrides must be a no-op.
+    if changed := applyManagedCronJobOverrides(mcj, cj); changed {
+        t.Error("applyManagedCronJobOverrides() second call changed = true, want false (idempotent)")
+    }
+}
+
+func TestSetEnvOverrides(t *testing.T) {
+    tests := []struct {
+        name      string
+        env       []corev1.EnvVar
+        overrides []corev1.EnvVar
+        wantEnv   []corev1.EnvVar
+        wantChg   bool
+    }{
+        {
+            name:      "override replaces existing value",
+            env:       []corev1.EnvVar{{Name: "FOO", Value: "old"}},
+            overrides: []corev1.EnvVar{{Name: "FOO", Value: "new"}},
+            wantEnv:   []corev1.EnvVar{{Name: "FOO", Value: "new"}},
+            wantChg:   true,
+        },
+        {
+            name:      "override appends a new var",
+            env:       []corev1.EnvVar{{Name: "FOO", Value: "old"}},
+            overrides: []corev1.EnvVar{{Name: "BAR", Value: "new"}},
+            wantEnv:   []corev1.EnvVar{{Name: "FOO", Value: "old"}, {Name: "BAR", Value: "new"}},
+            wantChg:   true,
+        },
+        {
+            name:      "identical override is a no-op",
+            env:       []corev1.EnvVar{{Name: "FOO", Value: "same"}},
+            overrides: []corev1.EnvVar{{Name: "FOO", Value: "same"}},
+            wantEnv:   []corev1.EnvVar{{Name: "FOO", Value: "same"}},
+            wantChg:   false,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            c := &corev1.Container{Env: append([]corev1.EnvVar{}, tt.env...)}
+            got := setEnvOverrides(c, tt.overrides)
+            if got != tt.wantChg {
+                t.Errorf("setEnvOverrides() changed = %v, want %v", got, tt.wantChg)
+            }
+            if len(c.Env) != len(tt.wantEnv) {
+                t.Fatalf("setEnvOverrides() env = %v, want %v", c.Env, tt.wantEnv)
+            }
+            for i, e := range tt.wantEnv {
+                if c.Env[i] != e {
+                    t.Errorf("setEnvOverrides() env[%d] = %v, want %v", i, c.Env[i], e)
+                }
+            }
+        })
+    }
+}
+
+func TestFindEnvByName(t *testing.T) {
+    env := []corev1.EnvVar{{Name: "FOO", Value: "1"}, {Name: "BAR", Value: "2"}}
+
+    if got := findEnvByName(env, "BAR"); got == nil || got.Value != "2" {
+        t.Errorf("findEnvByName(BAR) = %v, want Value 2", got)
+    }
+    if got := findEnvByName(env, "MISSING"); got != nil {
+        t.Errorf("findEnvByName(MISSING) = %v, want nil", got)
+    }
+}
+
+func TestAddImagePullSecrets(t *testing.T) {
+    podSpec := &corev1.PodSpec{ImagePullSecrets: []corev1.LocalObjectReference{{Name: "existing"}}}
+
+    changed := addImagePullSecrets(podSpec, []corev1.LocalObjectReference{{Name: "existing"}, {Name: "new"}})
+    if !changed {
+        t.Fatal("addImagePullSecrets() = false, want true")
+    }
+    if len(podSpec.ImagePullSecrets) != 2 {
+        t.Fatalf("ImagePullSecrets = %v, want 2 entries", podSpec.ImagePullSecrets)
+    }
+
+    // Re-adding the same secrets must be a no-op.
+    if changed := addImagePullSecrets(podSpec, []corev1.LocalObjectReference{{Name: "existing"}, {Name: "new"}}); changed {
+        t.Error("addImagePullSecrets() second call changed = true, want false (idempotent)")
+    }
+}
+
+func TestCreateCronJobRequiresScheduleOverride(t *testing.T) {
+    r := &ManagedCronJobReconciler{}
+    mcj := &v1alpha1.ManagedCronJob{
+        Spec: v1alpha1.ManagedCronJobSpec{CronJobName: "missing-cronjob"},
+    }
+
+    if _, err := r.createCronJob(nil, mcj); err == nil {
+        t.Fatal("createCronJob() error = nil, want error when ScheduleOverride is unset")
+    }
+}
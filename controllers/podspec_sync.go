@@ -0,0 +1,125 @@
+package controllers
+
+import (
+    "reflect"
+
+    appsv1 "k8s.io/api/apps/v1"
+    batchv1 "k8s.io/api/batch/v1"
+    corev1 "k8s.io/api/core/v1"
+
+    "github.com/example/cronjob-controller/api/v1alpha1"
+)
+
+// syncPodSpecFields projects the PodSpec fields named in mcj's SyncPolicy from deploy's pod
+// template onto cj's job template, in addition to the container images synced by Reconcile.
+// Env vars are merged by name with the job template's own vars winning; resources are copied
+// per-container using the ContainerMappings declared for deploy. It reports whether cj changed.
+func syncPodSpecFields(mcj *v1alpha1.ManagedCronJob, deploy *appsv1.Deployment, cj *batchv1.CronJob) bool {
+    ref := findSourceDeploymentRef(mcj, deploy.Name)
+    if ref == nil || len(mcj.Spec.SyncPolicy.Fields) == 0 {
+        return false
+    }
+
+    fields := make(map[v1alpha1.SyncField]bool, len(mcj.Spec.SyncPolicy.Fields))
+    for _, f := range mcj.Spec.SyncPolicy.Fields {
+        fields[f] = true
+    }
+
+    podSpec := &cj.Spec.JobTemplate.Spec.Template.Spec
+    srcPodSpec := &deploy.Spec.Template.Spec
+    changed := false
+
+    if fields[v1alpha1.SyncFieldNodeSelector] && !reflect.DeepEqual(podSpec.NodeSelector, srcPodSpec.NodeSelector) {
+        podSpec.NodeSelector = srcPodSpec.NodeSelector
+        changed = true
+    }
+    if fields[v1alpha1.SyncFieldTolerations] && !reflect.DeepEqual(podSpec.Tolerations, srcPodSpec.Tolerations) {
+        podSpec.Tolerations = srcPodSpec.Tolerations
+        changed = true
+    }
+    if fields[v1alpha1.SyncFieldAffinity] && !reflect.DeepEqual(podSpec.Affinity, srcPodSpec.Affinity) {
+        podSpec.Affinity = srcPodSpec.Affinity.DeepCopy()
+        changed = true
+    }
+
+    if fields[v1alpha1.SyncFieldResources] || fields[v1alpha1.SyncFieldEnv] {
+        deploymentContainerFor := containerNameMapper(ref)
+        for i := range podSpec.Containers {
+            c := &podSpec.Containers[i]
+            srcName, ok := deploymentContainerFor(c.Name)
+            if !ok {
+                continue
+            }
+            srcContainer := findContainerByName(srcPodSpec.Containers, srcName)
+            if srcContainer == nil {
+                continue
+            }
+
+            if fields[v1alpha1.SyncFieldResources] && !reflect.DeepEqual(c.Resources, srcContainer.Resources) {
+                c.Resources = *srcContainer.Resources.DeepCopy()
+                changed = true
+            }
+            if fields[v1alpha1.SyncFieldEnv] && mergeEnvByName(c, srcContainer.Env) {
+                changed = true
+            }
+        }
+    }
+
+    return changed
+}
+
+// findSourceDeploymentRef returns the SourceDeploymentRef mcj declares for deploymentName, or nil
+// if mcj does not track that Deployment.
+func findSourceDeploymentRef(mcj *v1alpha1.ManagedCronJob, deploymentName string) *v1alpha1.SourceDeploymentRef {
+    for i := range mcj.Spec.SourceDeployments {
+        if mcj.Spec.SourceDeployments[i].Name == deploymentName {
+            return &mcj.Spec.SourceDeployments[i]
+        }
+    }
+    return nil
+}
+
+// containerNameMapper returns a lookup from a CronJob container name to the source Deployment
+// container name it tracks, per ref.ContainerMappings. With no mappings declared, containers
+// are matched by identical name.
+func containerNameMapper(ref *v1alpha1.SourceDeploymentRef) func(cronJobContainer string) (string, bool) {
+    if len(ref.ContainerMappings) == 0 {
+        return func(name string) (string, bool) { return name, true }
+    }
+    byName := make(map[string]string, len(ref.ContainerMappings))
+    for _, m := range ref.ContainerMappings {
+        byName[m.CronJobContainer] = m.DeploymentContainer
+    }
+    return func(name string) (string, bool) {
+        deploymentContainer, ok := byName[name]
+        return deploymentContainer, ok
+    }
+}
+
+func findContainerByName(containers []corev1.Container, name string) *corev1.Container {
+    for i := range containers {
+        if containers[i].Name == name {
+            return &containers[i]
+        }
+    }
+    return nil
+}
+
+// mergeEnvByName appends entries from srcEnv whose name is not already set on c, so
+// CronJob-declared env vars take precedence over those propagated from the Deployment.
+func mergeEnvByName(c *corev1.Container, srcEnv []corev1.EnvVar) bool {
+    existing := make(map[string]bool, len(c.Env))
+    for _, e := range c.Env {
+        existing[e.Name] = true
+    }
+
+    changed := false
+    for _, e := range srcEnv {
+        if existing[e.Name] {
+            continue
+        }
+        c.Env = append(c.Env, e)
+        changed = true
+    }
+    return changed
+}
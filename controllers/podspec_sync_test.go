@@ -0,0 +1,140 @@
+package controllers
+
+import (
+    "testing"
+
+    appsv1 "k8s.io/api/apps/v1"
+    batchv1 "k8s.io/api/batch/v1"
+    corev1 "k8s.io/api/core/v1"
+    metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+    "github.com/example/cronjob-controller/api/v1alpha1"
+)
+
+func TestMergeEnvByName(t *testing.T) {
+    tests := []struct {
+        name     string
+        existing []corev1.EnvVar
+        src      []corev1.EnvVar
+        wantEnv  []corev1.EnvVar
+        wantChg  bool
+    }{
+        {
+            name:     "appends new vars",
+            existing: []corev1.EnvVar{{Name: "A", Value: "1"}},
+            src:      []corev1.EnvVar{{Name: "B", Value: "2"}},
+            wantEnv:  []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}},
+            wantChg:  true,
+        },
+        {
+            name:     "existing var wins, no change",
+            existing: []corev1.EnvVar{{Name: "A", Value: "1"}},
+            src:      []corev1.EnvVar{{Name: "A", Value: "override"}},
+            wantEnv:  []corev1.EnvVar{{Name: "A", Value: "1"}},
+            wantChg:  false,
+        },
+        {
+            name:     "empty src is a no-op",
+            existing: []corev1.EnvVar{{Name: "A", Value: "1"}},
+            src:      nil,
+            wantEnv:  []corev1.EnvVar{{Name: "A", Value: "1"}},
+            wantChg:  false,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            c := &corev1.Container{Env: append([]corev1.EnvVar{}, tt.existing...)}
+            got := mergeEnvByName(c, tt.src)
+            if got != tt.wantChg {
+                t.Errorf("mergeEnvByName() changed = %v, want %v", got, tt.wantChg)
+            }
+            if len(c.Env) != len(tt.wantEnv) {
+                t.Fatalf("mergeEnvByName() env = %v, want %v", c.Env, tt.wantEnv)
+            }
+            for i, e := range tt.wantEnv {
+                if c.Env[i] != e {
+                    t.Errorf("mergeEnvByName() env[%d] = %v, want %v", i, c.Env[i], e)
+                }
+            }
+        })
+    }
+}
+
+func TestContainerNameMapper(t *testing.T) {
+    t.Run("no mappings matches by identical name", func(t *testing.T) {
+        mapper := containerNameMapper(&v1alpha1.SourceDeploymentRef{})
+        name, ok := mapper("worker")
+        if !ok || name != "worker" {
+            t.Errorf("mapper(%q) = (%q, %v), want (%q, true)", "worker", name, ok, "worker")
+        }
+    })
+
+    t.Run("explicit mapping overrides name", func(t *testing.T) {
+        ref := &v1alpha1.SourceDeploymentRef{
+            ContainerMappings: []v1alpha1.ContainerMapping{
+                {CronJobContainer: "job-worker", DeploymentContainer: "worker"},
+            },
+        }
+        mapper := containerNameMapper(ref)
+
+        if name, ok := mapper("job-worker"); !ok || name != "worker" {
+            t.Errorf("mapper(%q) = (%q, %v), want (%q, true)", "job-worker", name, ok, "worker")
+        }
+        if _, ok := mapper("unmapped"); ok {
+            t.Errorf("mapper(%q) ok = true, want false", "unmapped")
+        }
+    })
+}
+
+func TestSyncPodSpecFields(t *testing.T) {
+    mcj := &v1alpha1.ManagedCronJob{
+        Spec: v1alpha1.ManagedCronJobSpec{
+            SourceDeployments: []v1alpha1.SourceDeploymentRef{{Name: "app"}},
+            SyncPolicy:        v1alpha1.SyncPolicy{Fields: []v1alpha1.SyncField{v1alpha1.SyncFieldNodeSelector, v1alpha1.SyncFieldEnv}},
+        },
+    }
+    deploy := &appsv1.Deployment{
+        ObjectMeta: metav1.ObjectMeta{Name: "app"},
+        Spec: appsv1.DeploymentSpec{
+            Template: corev1.PodTemplateSpec{
+                Spec: corev1.PodSpec{
+                    NodeSelector: map[string]string{"disk": "ssd"},
+                    Containers: []corev1.Container{
+                        {Name: "app", Env: []corev1.EnvVar{{Name: "FOO", Value: "bar"}}},
+                    },
+                },
+            },
+        },
+    }
+    cj := &batchv1.CronJob{
+        Spec: batchv1.CronJobSpec{
+            JobTemplate: batchv1.JobTemplateSpec{
+                Spec: batchv1.JobSpec{
+                    Template: corev1.PodTemplateSpec{
+                        Spec: corev1.PodSpec{
+                            Containers: []corev1.Container{{Name: "app"}},
+                        },
+                    },
+                },
+            },
+        },
+    }
+
+    if changed := syncPodSpecFields(mcj, deploy, cj); !changed {
+        t.Fatal("syncPodSpecFields() = false, want true")
+    }
+
+    podSpec := cj.Spec.JobTemplate.Spec.Template.Spec
+    if podSpec.NodeSelector["disk"] != "ssd" {
+        t.Errorf("nodeSelector not synced, got %v", podSpec.NodeSelector)
+    }
+    if len(podSpec.Containers[0].Env) != 1 || podSpec.Containers[0].Env[0].Name != "FOO" {
+        t.Errorf("env not synced, got %v", podSpec.Containers[0].Env)
+    }
+
+    // A second sync from the same inputs should be a no-op.
+    if changed := syncPodSpecFields(mcj, deploy, cj); changed {
+        t.Error("syncPodSpecFields() second call changed = true, want false (idempotent)")
+    }
+}
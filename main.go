@@ -4,10 +4,12 @@ import (
     "context"
     "flag"
     "os"
+    "time"
 	"fmt"
 
     appsv1 "k8s.io/api/apps/v1"
     batchv1 "k8s.io/api/batch/v1"
+    "k8s.io/apimachinery/pkg/labels"
     "k8s.io/apimachinery/pkg/runtime"
     utilruntime "k8s.io/apimachinery/pkg/util/runtime"
     clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -16,6 +18,7 @@ import (
     "sigs.k8s.io/controller-runtime/pkg/healthz"
     "go.opentelemetry.io/otel"
 
+    "github.com/example/cronjob-controller/api/v1alpha1"
     "github.com/example/cronjob-controller/controllers"
 )
 
@@ -28,20 +31,52 @@ func init() {
     utilruntime.Must(clientgoscheme.AddToScheme(scheme))
     utilruntime.Must(appsv1.AddToScheme(scheme))
     utilruntime.Must(batchv1.AddToScheme(scheme))
+    utilruntime.Must(v1alpha1.AddToScheme(scheme))
 }
 
 func main() {
     var metricsAddr string
     var enableLeaderElection bool
     var probeAddr string
+    var otelExporter string
+    var runOnUpdate bool
+    var deleteSuccessfulAfter time.Duration
+    var deleteFailedAfter time.Duration
+    var deletePendingAfter time.Duration
+    var deleteOrphanedPodsAfter time.Duration
+    var cleanupLabelSelector string
 	fmt.Println("Starting cronjob-controller...")
     flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
     flag.StringVar(&probeAddr, "health-probe-addr", ":8081", "The address the probe endpoint binds to.")
     flag.BoolVar(&enableLeaderElection, "leader-elect", false,
         "Enable leader election for controller manager.")
+    flag.StringVar(&otelExporter, "otel-exporter", "stdout", "OpenTelemetry trace exporter to use: \"otlp-grpc\", \"otlp-http\", or \"stdout\".")
+    flag.BoolVar(&runOnUpdate, "run-on-update", false,
+        "Allow CronJobs annotated with controller.example.com/run-on-update=true to trigger an on-demand Job after an image sync.")
+    flag.DurationVar(&deleteSuccessfulAfter, "delete-successful-after", 0,
+        "Delete Completed Jobs matching --cleanup-label-selector this long after they finished. 0 disables.")
+    flag.DurationVar(&deleteFailedAfter, "delete-failed-after", 0,
+        "Delete Failed Jobs matching --cleanup-label-selector this long after they finished. 0 disables.")
+    flag.DurationVar(&deletePendingAfter, "delete-pending-after", 0,
+        "Delete Jobs matching --cleanup-label-selector that have not started this long after creation. 0 disables.")
+    flag.DurationVar(&deleteOrphanedPodsAfter, "delete-orphaned-pods-after", 0,
+        "Delete terminated Pods matching --cleanup-label-selector with no controller owner this long after they finished. 0 disables.")
+    flag.StringVar(&cleanupLabelSelector, "cleanup-label-selector", "",
+        "Label selector Jobs/Pods must match to be considered by the cleanup controller. Empty disables cleanup entirely.")
     flag.Parse()
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
 
+    shutdownTracing, err := initTracerProvider(context.Background(), otelExporter)
+    if err != nil {
+        setupLog.Error(err, "unable to initialize tracing")
+        os.Exit(1)
+    }
+    defer func() {
+        if err := shutdownTracing(context.Background()); err != nil {
+            setupLog.Error(err, "error shutting down tracer provider")
+        }
+    }()
+
     // Optionally restrict controller to a single namespace by setting WATCH_NAMESPACE
     // environment variable. If empty, the controller watches all namespaces.
     //watchNamespace := os.Getenv("WATCH_NAMESPACE")
@@ -67,17 +102,53 @@ func main() {
         os.Exit(1)
     }
 
+	fmt.Println("Registering ManagedCronJob controller with manager...")
+    if err = (&controllers.ManagedCronJobReconciler{
+        Client: mgr.GetClient(),
+        Scheme: mgr.GetScheme(),
+    }).SetupWithManager(mgr); err != nil {
+        setupLog.Error(err, "unable to create controller", "controller", "ManagedCronJob")
+        os.Exit(1)
+    }
+
 	fmt.Println("Registering Deployment controller with manager...")
     if err = (&controllers.DeploymentReconciler{
-        Client:   mgr.GetClient(),
-        Scheme:   mgr.GetScheme(),
-        Recorder: mgr.GetEventRecorderFor("cronjob-controller"),
-        Tracer:   otel.Tracer("cronjob-controller"),
+        Client:             mgr.GetClient(),
+        Scheme:             mgr.GetScheme(),
+        Recorder:           mgr.GetEventRecorderFor("cronjob-controller"),
+        Tracer:             otel.Tracer("cronjob-controller"),
+        RunOnUpdateEnabled: runOnUpdate,
     }).SetupWithManager(mgr); err != nil {
         setupLog.Error(err, "unable to create controller", "controller", "Deployment")
         os.Exit(1)
     }
 
+    // An empty --cleanup-label-selector disables cleanup entirely rather than matching every
+    // Job/Pod in the cluster, so operators must opt in per namespace or workload.
+    cleanupSelector := labels.Nothing()
+    if cleanupLabelSelector != "" {
+        cleanupSelector, err = labels.Parse(cleanupLabelSelector)
+        if err != nil {
+            setupLog.Error(err, "invalid --cleanup-label-selector")
+            os.Exit(1)
+        }
+    }
+
+	fmt.Println("Registering JobCleanup controller with manager...")
+    if err = (&controllers.JobCleanupReconciler{
+        Client:                  mgr.GetClient(),
+        Scheme:                  mgr.GetScheme(),
+        Recorder:                mgr.GetEventRecorderFor("cronjob-cleanup-controller"),
+        LabelSelector:           cleanupSelector,
+        DeleteSuccessfulAfter:   deleteSuccessfulAfter,
+        DeleteFailedAfter:       deleteFailedAfter,
+        DeletePendingAfter:      deletePendingAfter,
+        DeleteOrphanedPodsAfter: deleteOrphanedPodsAfter,
+    }).SetupWithManager(mgr); err != nil {
+        setupLog.Error(err, "unable to create controller", "controller", "JobCleanup")
+        os.Exit(1)
+    }
+
     if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
         setupLog.Error(err, "unable to set up health check")
         os.Exit(1)
@@ -92,5 +163,4 @@ func main() {
         setupLog.Error(err, "problem running manager")
         os.Exit(1)
     }
-    _ = context.Background()
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+    "context"
+    "fmt"
+
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+    "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/sdk/resource"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// initTracerProvider builds and registers a TracerProvider for the given --otel-exporter value
+// ("otlp-grpc", "otlp-http", or "stdout"), returning a shutdown func the caller must defer-call.
+func initTracerProvider(ctx context.Context, exporter string) (func(context.Context) error, error) {
+    res, err := resource.Merge(
+        resource.Default(),
+        resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String("cronjob-controller")),
+    )
+    if err != nil {
+        return nil, fmt.Errorf("building otel resource: %w", err)
+    }
+
+    var spanExporter sdktrace.SpanExporter
+    switch exporter {
+    case "otlp", "otlp-grpc":
+        spanExporter, err = otlptracegrpc.New(ctx)
+        if err != nil {
+            return nil, fmt.Errorf("creating otlp grpc exporter: %w", err)
+        }
+    case "otlp-http":
+        spanExporter, err = otlptracehttp.New(ctx)
+        if err != nil {
+            return nil, fmt.Errorf("creating otlp http exporter: %w", err)
+        }
+    case "stdout":
+        spanExporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+        if err != nil {
+            return nil, fmt.Errorf("creating stdout exporter: %w", err)
+        }
+    default:
+        return nil, fmt.Errorf("unknown --otel-exporter %q, expected \"otlp-grpc\", \"otlp-http\", or \"stdout\"", exporter)
+    }
+
+    tp := sdktrace.NewTracerProvider(
+        sdktrace.WithBatcher(spanExporter),
+        sdktrace.WithResource(res),
+    )
+    otel.SetTracerProvider(tp)
+    otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+    return tp.Shutdown, nil
+}